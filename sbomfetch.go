@@ -5,35 +5,209 @@ package main
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"hash"
 	"io"
+	"math/rand"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/klauspost/compress/zstd"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/remote"
+	rekorclient "github.com/sigstore/rekor/pkg/client"
 	"github.com/ulikunitz/xz"
+	"github.com/vbauerster/mpb/v8"
+	"github.com/vbauerster/mpb/v8/decor"
+	"golang.org/x/term"
+	"lukechampine.com/blake3"
 )
 
+// defaultRekorURL is the public Rekor transparency log used when verifying
+// attestations unless --insecure-ignore-tlog is set.
+const defaultRekorURL = "https://rekor.sigstore.dev"
+
+// AttestationVerifyOptions mirrors the flags `cosign verify-attestation`
+// exposes for restricting which signing identity is accepted.
+type AttestationVerifyOptions struct {
+	CertificateIdentity         string
+	CertificateIdentityRegexp   string
+	CertificateOidcIssuer       string
+	CertificateOidcIssuerRegexp string
+	InsecureIgnoreTlog          bool
+}
+
+// Codec identifies the compression format wrapping a tar archive.
+type Codec int
+
+const (
+	CodecUnknown Codec = iota
+	CodecGzip
+	CodecXz
+	CodecBzip2
+	CodecZstd
+)
+
+var (
+	gzipMagic  = []byte{0x1F, 0x8B}
+	xzMagic    = []byte{0xFD, 0x37, 0x7A, 0x58, 0x5A}
+	bzip2Magic = []byte{0x42, 0x5A, 0x68}
+	zstdMagic  = []byte{0x28, 0xB5, 0x2F, 0xFD}
+)
+
+// detectCompression sniffs the magic bytes at the start of r and returns the
+// codec they identify, so callers don't have to trust a URL's file extension.
+func detectCompression(r io.ReaderAt) (Codec, error) {
+	header := make([]byte, 6)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return CodecUnknown, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return CodecGzip, nil
+	case bytes.HasPrefix(header, xzMagic):
+		return CodecXz, nil
+	case bytes.HasPrefix(header, bzip2Magic):
+		return CodecBzip2, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		return CodecZstd, nil
+	default:
+		return CodecUnknown, fmt.Errorf("unrecognized archive magic bytes: % x", header)
+	}
+}
+
 type SPDXSBOM struct {
 	Packages      []Package      `json:"packages"`
 	Relationships []Relationship `json:"relationships"`
 }
 
 type Package struct {
-	SPDXID           string `json:"SPDXID"`
-	Name             string `json:"name"`
-	DownloadLocation string `json:"downloadLocation"`
+	SPDXID           string     `json:"SPDXID"`
+	Name             string     `json:"name"`
+	DownloadLocation string     `json:"downloadLocation"`
+	Checksums        []Checksum `json:"checksums"`
+}
+
+// SourceKind distinguishes the transport a downloadLocation requires.
+type SourceKind int
+
+const (
+	SourceKindTarball SourceKind = iota
+	SourceKindGit
+)
+
+// GitLocator is a downloadLocation parsed as an SPDX VCS locator, e.g.
+// "git+https://github.com/foo/bar@abc123" or
+// "git+https://github.com/foo/bar.git#tag=v1.2.3".
+type GitLocator struct {
+	RepoURL string
+	Ref     string // commit SHA, tag name, or branch name
+	RefType string // "commit", "tag", "branch", or "" for the default branch
+}
+
+// detectSourceKind reports whether downloadLocation is a VCS locator
+// ("git+https://", "git+ssh://", "git://") or an ordinary tarball URL.
+func detectSourceKind(downloadLocation string) SourceKind {
+	lower := strings.ToLower(downloadLocation)
+	if strings.HasPrefix(lower, "git+") || strings.HasPrefix(lower, "git://") {
+		return SourceKindGit
+	}
+	return SourceKindTarball
+}
+
+// parseGitLocation parses the SPDX VCS locator suffixes documented in the
+// SPDX specification's download location Annex: "@<commit>" pins a commit,
+// and "#tag=<name>" / "#branch=<name>" pin a tag or branch.
+func parseGitLocation(downloadLocation string) GitLocator {
+	repoURL := strings.TrimPrefix(downloadLocation, "git+")
+
+	locator := GitLocator{RepoURL: repoURL}
+
+	if hashIdx := strings.Index(repoURL, "#"); hashIdx != -1 {
+		fragment := repoURL[hashIdx+1:]
+		repoURL = repoURL[:hashIdx]
+		switch {
+		case strings.HasPrefix(fragment, "tag="):
+			locator.Ref = strings.TrimPrefix(fragment, "tag=")
+			locator.RefType = "tag"
+		case strings.HasPrefix(fragment, "branch="):
+			locator.Ref = strings.TrimPrefix(fragment, "branch=")
+			locator.RefType = "branch"
+		}
+	}
+
+	// A commit pin looks like ".../repo@abc123". Only look for "@" after the
+	// last "/" so we don't mistake the "user@host" of a git+ssh URL for one.
+	if atIdx := strings.LastIndex(repoURL, "@"); atIdx > strings.LastIndex(repoURL, "/") {
+		locator.Ref = repoURL[atIdx+1:]
+		locator.RefType = "commit"
+		repoURL = repoURL[:atIdx]
+	}
+
+	locator.RepoURL = repoURL
+	return locator
+}
+
+// Checksum is an SPDX checksum entry declaring a digest of the package's
+// download content under a named algorithm (e.g. "SHA256").
+type Checksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// ChecksumMismatchError reports that a downloaded file's computed digest
+// did not match the value declared in the SBOM.
+type ChecksumMismatchError struct {
+	Algorithm string
+	Want      string
+	Got       string
+}
+
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch (%s): want %s, got %s", e.Algorithm, e.Want, e.Got)
+}
+
+// checksumHashers maps a normalized SPDX algorithm name to its hash.Hash
+// constructor.
+var checksumHashers = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"md5":    md5.New,
+	"blake3": func() hash.Hash { return blake3.New(32, nil) },
+}
+
+// normalizeAlgorithm lower-cases and strips hyphens from an SPDX checksum
+// algorithm name (e.g. "SHA-256" -> "sha256") so it can key checksumHashers.
+func normalizeAlgorithm(algorithm string) string {
+	return strings.ToLower(strings.ReplaceAll(algorithm, "-", ""))
 }
 
 type Relationship struct {
@@ -47,19 +221,55 @@ type DownloadJob struct {
 	Index       int
 	Total       int
 	APKPackages []string
+	Checksums   []Checksum
+	Kind        SourceKind
+	GitRef      *GitLocator
 }
 
 type DownloadResult struct {
-	URL         string
-	APKPackages []string
-	Error       error
+	URL                string
+	APKPackages        []string
+	Error              error
+	VerifiedAlgorithms []string
+	FilePath           string
+	ResolvedCommit     string
+	Retries            int
+	BytesTransferred   int64
+	ElapsedSeconds     float64
 }
 
 type DownloadSummary struct {
-	SuccessCount int
-	FailureCount int
-	Files        []string
-	FilePackages map[string][]string
+	SuccessCount  int
+	FailureCount  int
+	Files         []string
+	FilePackages  map[string][]string
+	ChecksumTally map[string]int
+	GitProvenance map[string]string // download URL -> resolved commit SHA
+	RetryCount    int
+	Reports       []ReportEntry
+}
+
+// ReportEntry is one source's outcome in the machine-readable --report
+// summary.
+type ReportEntry struct {
+	URL                string   `json:"url"`
+	Status             string   `json:"status"` // "success" or "failed"
+	Filename           string   `json:"filename,omitempty"`
+	BytesTransferred   int64    `json:"bytesTransferred"`
+	ElapsedSeconds     float64  `json:"elapsedSeconds"`
+	VerifiedAlgorithms []string `json:"verifiedAlgorithms,omitempty"`
+	ResolvedCommit     string   `json:"resolvedCommit,omitempty"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// RunReport is the top-level document written by --report, so CI pipelines
+// can assert that every source for an image was retrieved and verified.
+type RunReport struct {
+	Image          string            `json:"image,omitempty"`
+	SignerIdentity string            `json:"signerIdentity,omitempty"`
+	RekorLogIndex  int64             `json:"rekorLogIndex,omitempty"`
+	Downloads      []ReportEntry     `json:"downloads"`
+	Extraction     ExtractionSummary `json:"extraction"`
 }
 
 type ExtractionSummary struct {
@@ -70,13 +280,42 @@ type ExtractionSummary struct {
 type PackageMapping struct {
 	URL         string
 	APKPackages []string
+	Checksums   []Checksum
+	Kind        SourceKind
+	GitRef      *GitLocator
 }
 
 func main() {
 	var concurrency = flag.Int("concurrency", 4, "Number of concurrent downloads")
 	var platform = flag.String("platform", "linux/amd64", "Platform for container image")
+	var requireChecksums = flag.Bool("require-checksums", false, "Fail the run if any source package has no usable checksum")
+	var trustAlgorithmsFlag = flag.String("trust-algorithms", "", "Comma-separated list of checksum algorithms to trust (e.g. sha256,sha512,blake3); empty trusts all supported algorithms")
+	var maxRetries = flag.Int("max-retries", 5, "Maximum number of retries per download before giving up")
+	var retryBaseDelay = flag.Duration("retry-base-delay", 500*time.Millisecond, "Base delay for exponential backoff between retries")
+	var httpTimeout = flag.Duration("http-timeout", 30*time.Second, "Timeout for a single HTTP request")
+	var certIdentity = flag.String("certificate-identity", "", "The identity expected in the signing certificate's SAN")
+	var certIdentityRegexp = flag.String("certificate-identity-regexp", "", "A regexp the signing certificate's SAN identity must match")
+	var certOidcIssuer = flag.String("certificate-oidc-issuer", "", "The OIDC issuer expected in the signing certificate")
+	var certOidcIssuerRegexp = flag.String("certificate-oidc-issuer-regexp", "", "A regexp the signing certificate's OIDC issuer must match")
+	var insecureIgnoreTlog = flag.Bool("insecure-ignore-tlog", false, "Skip transparency log (Rekor) verification (insecure)")
+	var reportPath = flag.String("report", "", "Write a machine-readable JSON run report to this path")
 	flag.Parse()
 
+	trustAlgorithms := parseTrustAlgorithms(*trustAlgorithmsFlag)
+
+	attestOpts := AttestationVerifyOptions{
+		CertificateIdentity:         *certIdentity,
+		CertificateIdentityRegexp:   *certIdentityRegexp,
+		CertificateOidcIssuer:       *certOidcIssuer,
+		CertificateOidcIssuerRegexp: *certOidcIssuerRegexp,
+		InsecureIgnoreTlog:          *insecureIgnoreTlog,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	httpClient := &http.Client{Timeout: *httpTimeout}
+
 	if flag.NArg() < 1 || flag.NArg() > 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options] <sbom-file.json|container-image> [download-directory]\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Examples:\n")
@@ -114,10 +353,17 @@ func main() {
 
 	var sbomData []byte
 	var err error
+	var signerIdentity string
+	var logIndex int64
 
 	if isContainerImage(sbomInput) {
+		if attestOpts.CertificateIdentity == "" && attestOpts.CertificateIdentityRegexp == "" {
+			fmt.Fprintln(os.Stderr, "Error: --certificate-identity or --certificate-identity-regexp is required to verify the attestation signer; a Fulcio cert alone only proves *some* identity signed it, not one you trust")
+			os.Exit(1)
+		}
+
 		fmt.Printf("🔍 Retrieving SBOM from container image: %s\n", sbomInput)
-		sbomData, err = retrieveSBOMFromSigstore(sbomInput, *platform)
+		sbomData, signerIdentity, logIndex, err = retrieveSBOMFromSigstore(ctx, sbomInput, *platform, attestOpts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error retrieving SBOM from sigstore: %v\n", err)
 			os.Exit(1)
@@ -130,6 +376,7 @@ func main() {
 			os.Exit(1)
 		}
 		fmt.Printf("💾 SBOM saved to: %s\n", sbomPath)
+		fmt.Printf("🔏 Attestation verified: signed by %s (Rekor log index %d)\n", signerIdentity, logIndex)
 	} else {
 		fmt.Printf("🔍 Reading SBOM from file: %s\n", sbomInput)
 		sbomData, err = os.ReadFile(sbomInput)
@@ -139,7 +386,7 @@ func main() {
 		}
 	}
 
-	packageMappings, err := extractPackageMappingsFromData(sbomData)
+	packageMappings, err := extractPackageMappingsFromData(ctx, httpClient, sbomData)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error extracting package mappings: %v\n", err)
 		os.Exit(1)
@@ -152,7 +399,19 @@ func main() {
 		return
 	}
 
-	downloadSummary := downloadConcurrently(packageMappings, archivesDir, *concurrency)
+	if *requireChecksums {
+		if missing := missingUsableChecksums(packageMappings, trustAlgorithms); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --require-checksums set but %d source(s) have no usable checksum:\n", len(missing))
+			for _, url := range missing {
+				fmt.Fprintf(os.Stderr, "   - %s\n", url)
+			}
+			os.Exit(1)
+		}
+	}
+
+	progress := newProgressReporter(len(packageMappings))
+	downloadSummary := downloadConcurrently(ctx, packageMappings, archivesDir, *concurrency, trustAlgorithms, httpClient, *maxRetries, *retryBaseDelay, progress)
+	progress.wait()
 
 	fmt.Printf("🎁 Download complete. Files saved to: %s\n", downloadDir)
 	fmt.Printf("🗄️ Extracting %d archives...\n", len(downloadSummary.Files))
@@ -169,9 +428,100 @@ func main() {
 	fmt.Printf("🎉 Extractions: %d successful, %d failed (total: %d)\n",
 		extractionSummary.SuccessCount, extractionSummary.FailureCount,
 		extractionSummary.SuccessCount+extractionSummary.FailureCount)
+	if len(downloadSummary.ChecksumTally) > 0 {
+		fmt.Println("🔒 Checksums verified:")
+		for _, algorithm := range []string{"sha1", "sha256", "sha512", "md5", "blake3"} {
+			if count, ok := downloadSummary.ChecksumTally[algorithm]; ok {
+				fmt.Printf("   - %s: %d\n", algorithm, count)
+			}
+		}
+	}
+	if len(downloadSummary.GitProvenance) > 0 {
+		fmt.Println("🌱 Resolved git sources:")
+		for url, commit := range downloadSummary.GitProvenance {
+			fmt.Printf("   - %s @ %s\n", url, commit)
+		}
+	}
+	if downloadSummary.RetryCount > 0 {
+		fmt.Printf("🔁 Retries: %d\n", downloadSummary.RetryCount)
+	}
+
+	if *reportPath != "" {
+		report := RunReport{
+			Image:          sbomInput,
+			SignerIdentity: signerIdentity,
+			RekorLogIndex:  logIndex,
+			Downloads:      downloadSummary.Reports,
+			Extraction:     extractionSummary,
+		}
+		if !isContainerImage(sbomInput) {
+			report.Image = ""
+		}
+
+		reportBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling report: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*reportPath, reportBytes, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report to %s: %v\n", *reportPath, err)
+			os.Exit(1)
+		}
+		fmt.Printf("📝 Run report written to: %s\n", *reportPath)
+	}
+}
+
+// parseTrustAlgorithms turns a comma-separated --trust-algorithms value into
+// a lookup set of normalized algorithm names. An empty flag trusts everything
+// checksumHashers supports.
+func parseTrustAlgorithms(flagValue string) map[string]bool {
+	if strings.TrimSpace(flagValue) == "" {
+		return nil
+	}
+
+	trusted := make(map[string]bool)
+	for _, algorithm := range strings.Split(flagValue, ",") {
+		algorithm = normalizeAlgorithm(strings.TrimSpace(algorithm))
+		if algorithm != "" {
+			trusted[algorithm] = true
+		}
+	}
+	return trusted
 }
 
-func extractPackageMappingsFromData(data []byte) ([]PackageMapping, error) {
+// missingUsableChecksums returns the download URLs whose packages declare no
+// checksum usable under trustAlgorithms (nil trustAlgorithms means any
+// supported algorithm is usable).
+func missingUsableChecksums(mappings []PackageMapping, trustAlgorithms map[string]bool) []string {
+	var missing []string
+	for _, mapping := range mappings {
+		// Git sources are verified by the pinned commit/tag/branch itself,
+		// not an SPDX checksum.
+		if mapping.Kind == SourceKindGit {
+			continue
+		}
+		if !hasUsableChecksum(mapping.Checksums, trustAlgorithms) {
+			missing = append(missing, mapping.URL)
+		}
+	}
+	return missing
+}
+
+func hasUsableChecksum(checksums []Checksum, trustAlgorithms map[string]bool) bool {
+	for _, checksum := range checksums {
+		algorithm := normalizeAlgorithm(checksum.Algorithm)
+		if _, supported := checksumHashers[algorithm]; !supported {
+			continue
+		}
+		if trustAlgorithms != nil && !trustAlgorithms[algorithm] {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func extractPackageMappingsFromData(ctx context.Context, httpClient *http.Client, data []byte) ([]PackageMapping, error) {
 	var sbom SPDXSBOM
 	if err := json.Unmarshal(data, &sbom); err != nil {
 		return nil, fmt.Errorf("failed to parse SBOM JSON: %w", err)
@@ -217,18 +567,30 @@ func extractPackageMappingsFromData(data []byte) ([]PackageMapping, error) {
 
 	// Group by URL to collect all packages per download location
 	urlToPackages := make(map[string][]string)
+	urlToChecksums := make(map[string][]Checksum)
+	urlToKind := make(map[string]SourceKind)
 	for _, pkg := range sbom.Packages {
-		if pkg.DownloadLocation != "" &&
-			pkg.DownloadLocation != "NOASSERTION" &&
-			strings.HasPrefix(pkg.DownloadLocation, "http") {
-
-			if isTarball(pkg.DownloadLocation) {
-				apkPackages := sourceToAPKs[pkg.SPDXID]
-				if len(apkPackages) == 0 {
-					apkPackages = []string{"unknown"}
-				}
-				// Append all packages for this source to the URL mapping
-				urlToPackages[pkg.DownloadLocation] = append(urlToPackages[pkg.DownloadLocation], apkPackages...)
+		if pkg.DownloadLocation == "" || pkg.DownloadLocation == "NOASSERTION" {
+			continue
+		}
+
+		kind := detectSourceKind(pkg.DownloadLocation)
+		if kind == SourceKindTarball {
+			if !strings.HasPrefix(pkg.DownloadLocation, "http") || !isTarball(ctx, httpClient, pkg.DownloadLocation) {
+				continue
+			}
+		}
+
+		apkPackages := sourceToAPKs[pkg.SPDXID]
+		if len(apkPackages) == 0 {
+			apkPackages = []string{"unknown"}
+		}
+		// Append all packages for this source to the URL mapping
+		urlToPackages[pkg.DownloadLocation] = append(urlToPackages[pkg.DownloadLocation], apkPackages...)
+		urlToKind[pkg.DownloadLocation] = kind
+		if len(pkg.Checksums) > 0 {
+			if _, exists := urlToChecksums[pkg.DownloadLocation]; !exists {
+				urlToChecksums[pkg.DownloadLocation] = pkg.Checksums
 			}
 		}
 	}
@@ -245,16 +607,92 @@ func extractPackageMappingsFromData(data []byte) ([]PackageMapping, error) {
 				finalPackages = append(finalPackages, pkg)
 			}
 		}
-		mappings = append(mappings, PackageMapping{
+
+		mapping := PackageMapping{
 			URL:         url,
 			APKPackages: finalPackages,
-		})
+			Checksums:   urlToChecksums[url],
+			Kind:        urlToKind[url],
+		}
+		if mapping.Kind == SourceKindGit {
+			gitRef := parseGitLocation(url)
+			mapping.GitRef = &gitRef
+		}
+		mappings = append(mappings, mapping)
 	}
 
 	return mappings, nil
 }
 
-func downloadConcurrently(mappings []PackageMapping, archivesDir string, concurrency int) DownloadSummary {
+// progressReporter renders an overall completion bar plus a per-file transfer
+// bar for each in-flight download. On a non-TTY (CI logs, redirected output)
+// no mpb.Progress is created at all; it falls back to the plain Printf lines
+// already scattered through the download path, so every method is a no-op.
+type progressReporter struct {
+	progress    *mpb.Progress
+	overall     *mpb.Bar
+	interactive bool
+}
+
+// newProgressReporter builds a reporter tracking total downloads. Pass a nil
+// *progressReporter anywhere one is expected to disable progress entirely;
+// every method tolerates a nil receiver.
+func newProgressReporter(total int) *progressReporter {
+	interactive := term.IsTerminal(int(os.Stdout.Fd()))
+	if !interactive {
+		return &progressReporter{interactive: false}
+	}
+
+	p := mpb.New(mpb.WithWidth(40), mpb.WithRefreshRate(150*time.Millisecond))
+	overall := p.AddBar(int64(total),
+		mpb.PrependDecorators(decor.Name("packages ")),
+		mpb.AppendDecorators(decor.CountersNoUnit("%d / %d")),
+	)
+	return &progressReporter{progress: p, overall: overall, interactive: interactive}
+}
+
+// incrOverall advances the overall completion bar by one finished job.
+func (r *progressReporter) incrOverall() {
+	if r == nil || !r.interactive {
+		return
+	}
+	r.overall.Increment()
+}
+
+// wait blocks until every bar has finished rendering, so the final summary
+// lines print below a settled progress display rather than in the middle of it.
+func (r *progressReporter) wait() {
+	if r == nil || !r.interactive {
+		return
+	}
+	r.progress.Wait()
+}
+
+// wrapBody attaches a byte-count bar to body when running interactively;
+// otherwise it returns body unchanged. When contentLength is unknown (a
+// chunked response, or a server that omits Content-Length), it falls back
+// to a spinner that just shows bytes transferred so far.
+func (r *progressReporter) wrapBody(label string, contentLength int64, body io.ReadCloser) io.ReadCloser {
+	if r == nil || !r.interactive {
+		return body
+	}
+
+	var bar *mpb.Bar
+	if contentLength <= 0 {
+		bar = r.progress.AddSpinner(0,
+			mpb.PrependDecorators(decor.Name(label, decor.WCSyncSpaceR)),
+			mpb.AppendDecorators(decor.CurrentKibiByte("% .1f")),
+		)
+	} else {
+		bar = r.progress.AddBar(contentLength,
+			mpb.PrependDecorators(decor.Name(label, decor.WCSyncSpaceR)),
+			mpb.AppendDecorators(decor.CountersKibiByte("% .1f / % .1f")),
+		)
+	}
+	return bar.ProxyReader(body).(io.ReadCloser)
+}
+
+func downloadConcurrently(ctx context.Context, mappings []PackageMapping, archivesDir string, concurrency int, trustAlgorithms map[string]bool, httpClient *http.Client, maxRetries int, retryBaseDelay time.Duration, progress *progressReporter) DownloadSummary {
 	jobs := make(chan DownloadJob, len(mappings))
 	results := make(chan DownloadResult, len(mappings))
 
@@ -262,7 +700,7 @@ func downloadConcurrently(mappings []PackageMapping, archivesDir string, concurr
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
-		go worker(jobs, results, archivesDir, &wg)
+		go worker(ctx, jobs, results, archivesDir, trustAlgorithms, httpClient, maxRetries, retryBaseDelay, progress, &wg)
 	}
 
 	// Send jobs
@@ -272,6 +710,9 @@ func downloadConcurrently(mappings []PackageMapping, archivesDir string, concurr
 			jobs <- DownloadJob{
 				URL:         mapping.URL,
 				APKPackages: mapping.APKPackages,
+				Checksums:   mapping.Checksums,
+				Kind:        mapping.Kind,
+				GitRef:      mapping.GitRef,
 				Index:       i + 1,
 				Total:       len(mappings),
 			}
@@ -290,81 +731,393 @@ func downloadConcurrently(mappings []PackageMapping, archivesDir string, concurr
 	filePackages := make(map[string][]string)
 	successCount := 0
 	failureCount := 0
+	checksumTally := make(map[string]int)
+	gitProvenance := make(map[string]string)
+	retryCount := 0
+	var reports []ReportEntry
 
 	for result := range results {
 		completed++
+		retryCount += result.Retries
+
+		entry := ReportEntry{
+			URL:                result.URL,
+			BytesTransferred:   result.BytesTransferred,
+			ElapsedSeconds:     result.ElapsedSeconds,
+			VerifiedAlgorithms: result.VerifiedAlgorithms,
+			ResolvedCommit:     result.ResolvedCommit,
+		}
+
 		if result.Error != nil {
 			failureCount++
+			entry.Status = "failed"
+			entry.Error = result.Error.Error()
 			fmt.Fprintf(os.Stderr, "❌ Error downloading %s: %v\n", result.URL, result.Error)
 		} else {
 			successCount++
-			filename := getFilenameFromURL(result.URL)
-			filePath := filepath.Join(archivesDir, filename)
-			downloadedFiles = append(downloadedFiles, filePath)
-			filePackages[filePath] = result.APKPackages
-			fmt.Printf("📦 Downloaded (%d/%d): %s [%s]\n", completed, len(mappings), filename, strings.Join(result.APKPackages, ", "))
+			downloadedFiles = append(downloadedFiles, result.FilePath)
+			filePackages[result.FilePath] = result.APKPackages
+			for _, algorithm := range result.VerifiedAlgorithms {
+				checksumTally[algorithm]++
+			}
+			if result.ResolvedCommit != "" {
+				gitProvenance[result.URL] = result.ResolvedCommit
+			}
+			entry.Status = "success"
+			entry.Filename = filepath.Base(result.FilePath)
+			fmt.Printf("📦 Downloaded (%d/%d): %s [%s]\n", completed, len(mappings), filepath.Base(result.FilePath), strings.Join(result.APKPackages, ", "))
 		}
+
+		reports = append(reports, entry)
 	}
 
 	return DownloadSummary{
-		SuccessCount: successCount,
-		FailureCount: failureCount,
-		Files:        downloadedFiles,
-		FilePackages: filePackages,
+		SuccessCount:  successCount,
+		FailureCount:  failureCount,
+		Files:         downloadedFiles,
+		FilePackages:  filePackages,
+		ChecksumTally: checksumTally,
+		GitProvenance: gitProvenance,
+		RetryCount:    retryCount,
+		Reports:       reports,
 	}
 }
 
-func worker(jobs <-chan DownloadJob, results chan<- DownloadResult, archivesDir string, wg *sync.WaitGroup) {
+func worker(ctx context.Context, jobs <-chan DownloadJob, results chan<- DownloadResult, archivesDir string, trustAlgorithms map[string]bool, httpClient *http.Client, maxRetries int, retryBaseDelay time.Duration, progress *progressReporter, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for job := range jobs {
-		fmt.Printf("🚀 Starting download (%d/%d): %s [%s]\n", job.Index, job.Total, getFilenameFromURL(job.URL), strings.Join(job.APKPackages, ", "))
+		start := time.Now()
+
+		if job.Kind == SourceKindGit {
+			fmt.Printf("🚀 Starting clone (%d/%d): %s [%s]\n", job.Index, job.Total, job.GitRef.RepoURL, strings.Join(job.APKPackages, ", "))
+
+			filePath, resolvedCommit, err := gitFetcher(ctx, *job.GitRef, archivesDir)
+			results <- DownloadResult{
+				URL:              job.URL,
+				APKPackages:      job.APKPackages,
+				Error:            err,
+				FilePath:         filePath,
+				ResolvedCommit:   resolvedCommit,
+				BytesTransferred: fileSizeOrZero(filePath),
+				ElapsedSeconds:   time.Since(start).Seconds(),
+			}
+			progress.incrOverall()
+			continue
+		}
 
-		err := downloadFileToDir(job.URL, archivesDir)
+		filename := getFilenameFromURL(ctx, httpClient, job.URL)
+		fmt.Printf("🚀 Starting download (%d/%d): %s [%s]\n", job.Index, job.Total, filename, strings.Join(job.APKPackages, ", "))
+
+		verifiedAlgorithms, retries, err := downloadFileToDir(ctx, httpClient, job.URL, archivesDir, job.Checksums, trustAlgorithms, maxRetries, retryBaseDelay, progress)
+		filePath := filepath.Join(archivesDir, filename)
 		results <- DownloadResult{
-			URL:         job.URL,
-			APKPackages: job.APKPackages,
-			Error:       err,
+			URL:                job.URL,
+			APKPackages:        job.APKPackages,
+			Error:              err,
+			VerifiedAlgorithms: verifiedAlgorithms,
+			FilePath:           filePath,
+			Retries:            retries,
+			BytesTransferred:   fileSizeOrZero(filePath),
+			ElapsedSeconds:     time.Since(start).Seconds(),
 		}
+		progress.incrOverall()
+	}
+}
+
+// fileSizeOrZero returns path's size in bytes, or 0 if it can't be stat'd
+// (e.g. the download failed before producing a file).
+func fileSizeOrZero(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
 	}
+	return info.Size()
 }
 
-func isTarball(url string) bool {
+func isTarball(ctx context.Context, httpClient *http.Client, url string) bool {
 	lowerURL := strings.ToLower(url)
-	return strings.Contains(lowerURL, ".tar.gz") ||
+	if strings.Contains(lowerURL, ".tar.gz") ||
 		strings.Contains(lowerURL, ".tar.xz") ||
 		strings.Contains(lowerURL, ".tgz") ||
-		strings.Contains(lowerURL, ".tar.bz2")
+		strings.Contains(lowerURL, ".tar.bz2") ||
+		strings.Contains(lowerURL, ".tar.zst") {
+		return true
+	}
+
+	// No recognizable extension: the URL may still point at a tarball (many
+	// upstream source mirrors strip or mangle it), so sniff the real bytes.
+	return sniffsAsArchive(ctx, httpClient, url)
+}
+
+// sniffsAsArchive issues a ranged GET for just the first few bytes of url
+// and checks whether they match a known archive codec's magic number. It
+// uses the caller's ctx and httpClient so the request respects
+// --http-timeout and Ctrl-C cancellation like every other network call.
+func sniffsAsArchive(ctx context.Context, httpClient *http.Client, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Range", "bytes=0-5")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+
+	header, err := io.ReadAll(io.LimitReader(resp.Body, 6))
+	if err != nil {
+		return false
+	}
+
+	_, err = detectCompression(bytes.NewReader(header))
+	return err == nil
+}
+
+// downloadFileToDir downloads url into downloadDir, resuming into a ".part"
+// file across up to maxRetries retries with exponential backoff, and
+// verifies the completed file against checksums. trustAlgorithms restricts
+// which declared algorithms are considered (nil trusts every algorithm
+// downloadFileToDir knows how to compute). It returns the algorithms that
+// were successfully verified and the number of retries it took.
+func downloadFileToDir(ctx context.Context, httpClient *http.Client, url, downloadDir string, checksums []Checksum, trustAlgorithms map[string]bool, maxRetries int, retryBaseDelay time.Duration, progress *progressReporter) ([]string, int, error) {
+	filename := getFilenameFromURL(ctx, httpClient, url)
+	finalPath := filepath.Join(downloadDir, filename)
+	partPath := finalPath + ".part"
+
+	retries, err := fetchWithRetry(ctx, httpClient, url, partPath, maxRetries, retryBaseDelay, progress, filename)
+	if err != nil {
+		return nil, retries, err
+	}
+
+	verifiedAlgorithms, err := verifyChecksums(partPath, checksums, trustAlgorithms)
+	if err != nil {
+		os.Remove(partPath)
+		return nil, retries, err
+	}
+
+	if err := os.Rename(partPath, finalPath); err != nil {
+		return nil, retries, fmt.Errorf("failed to finalize downloaded file: %w", err)
+	}
+
+	return verifiedAlgorithms, retries, nil
 }
 
-func downloadFileToDir(url, downloadDir string) error {
-	resp, err := http.Get(url)
+// verifyChecksums hashes the file at path with every supported algorithm and
+// compares each checksum whose algorithm is both declared and trusted.
+func verifyChecksums(path string, checksums []Checksum, trustAlgorithms map[string]bool) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open downloaded file: %w", err)
+	}
+	defer file.Close()
+
+	hashers := make(map[string]hash.Hash, len(checksumHashers))
+	writers := make([]io.Writer, 0, len(checksumHashers))
+	for algorithm, newHasher := range checksumHashers {
+		hasher := newHasher()
+		hashers[algorithm] = hasher
+		writers = append(writers, hasher)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), file); err != nil {
+		return nil, fmt.Errorf("failed to hash downloaded file: %w", err)
+	}
+
+	var verifiedAlgorithms []string
+	for _, checksum := range checksums {
+		algorithm := normalizeAlgorithm(checksum.Algorithm)
+		hasher, ok := hashers[algorithm]
+		if !ok {
+			continue
+		}
+		if trustAlgorithms != nil && !trustAlgorithms[algorithm] {
+			continue
+		}
+
+		got := hex.EncodeToString(hasher.Sum(nil))
+		want := strings.ToLower(checksum.ChecksumValue)
+		if got != want {
+			return nil, ChecksumMismatchError{Algorithm: checksum.Algorithm, Want: checksum.ChecksumValue, Got: got}
+		}
+		verifiedAlgorithms = append(verifiedAlgorithms, algorithm)
+	}
+
+	return verifiedAlgorithms, nil
+}
+
+// resumeState tracks the identity of the in-progress download across retry
+// attempts, so a resumed Range request can detect that the server's content
+// changed out from under it.
+type resumeState struct {
+	etag         string
+	lastModified string
+}
+
+// fetchWithRetry streams url into partPath, appending across retries and
+// issuing a Range request to resume a prior partial attempt. It returns the
+// number of retries it took before success (or before giving up).
+func fetchWithRetry(ctx context.Context, httpClient *http.Client, url, partPath string, maxRetries int, baseDelay time.Duration, progress *progressReporter, label string) (int, error) {
+	var state resumeState
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return attempt, ctx.Err()
+			case <-time.After(backoffWithJitter(baseDelay, attempt)):
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return attempt, err
+		}
+
+		if err := attemptDownload(ctx, httpClient, url, partPath, &state, progress, label); err != nil {
+			lastErr = err
+			continue
+		}
+
+		return attempt, nil
+	}
+
+	return maxRetries, fmt.Errorf("exceeded %d retries: %w", maxRetries, lastErr)
+}
+
+// attemptDownload performs a single GET (or resuming Range GET) of url,
+// appending the response body to partPath.
+func attemptDownload(ctx context.Context, httpClient *http.Client, url, partPath string, state *resumeState, progress *progressReporter, label string) error {
+	existingSize := int64(0)
+	if info, err := os.Stat(partPath); err == nil {
+		existingSize = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if existingSize > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", existingSize))
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// The server ignored our Range request (or this is the first
+		// attempt); whatever bytes we already have are no longer valid.
+		if existingSize > 0 {
+			if err := os.Truncate(partPath, 0); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset partial download: %w", err)
+			}
+		}
+		state.etag = resp.Header.Get("ETag")
+		state.lastModified = resp.Header.Get("Last-Modified")
+	case http.StatusPartialContent:
+		if !resumeMatchesPriorAttempt(resp, *state) {
+			if err := os.Truncate(partPath, 0); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset partial download: %w", err)
+			}
+			return fmt.Errorf("server content for %s changed since last attempt", url)
+		}
+		if !contentRangeStartsAt(resp.Header.Get("Content-Range"), existingSize) {
+			if err := os.Truncate(partPath, 0); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset partial download: %w", err)
+			}
+			return fmt.Errorf("server returned 206 for %s but Content-Range did not resume from byte %d", url, existingSize)
+		}
+	default:
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
 
-	filename := getFilenameFromURL(url)
-	filepath := filepath.Join(downloadDir, filename)
-
-	out, err := os.Create(filepath)
+	out, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return fmt.Errorf("failed to open partial file: %w", err)
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to save file: %w", err)
+	body := progress.wrapBody(label, resp.ContentLength, resp.Body)
+	defer body.Close()
+
+	if _, err := io.Copy(out, body); err != nil {
+		return fmt.Errorf("failed to write partial file: %w", err)
 	}
 
 	return nil
 }
 
-func getFilenameFromURL(url string) string {
+// resumeMatchesPriorAttempt reports whether a 206 response's ETag or
+// Last-Modified header still matches the prior attempt recorded in state, so
+// we don't append bytes from a different underlying file.
+func resumeMatchesPriorAttempt(resp *http.Response, state resumeState) bool {
+	if state.etag == "" && state.lastModified == "" {
+		return true
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && state.etag != "" {
+		return etag == state.etag
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" && state.lastModified != "" {
+		return lastModified == state.lastModified
+	}
+	return true
+}
+
+// contentRangeStartsAt reports whether a "Content-Range: bytes start-end/total"
+// header value starts exactly at existingSize, so a server that returns 206
+// but ignores (or misinterprets) our Range request can't cause us to append
+// the wrong bytes onto an already-partial file.
+func contentRangeStartsAt(contentRange string, existingSize int64) bool {
+	if contentRange == "" {
+		return false
+	}
+	rangePart := strings.TrimPrefix(contentRange, "bytes ")
+	rangePart, _, ok := strings.Cut(rangePart, "/")
+	if !ok {
+		return false
+	}
+	startStr, _, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return false
+	}
+	return start == existingSize
+}
+
+// maxBackoff caps the computed delay between retries so a large attempt
+// count (or a large --retry-base-delay) can never overflow the shift below
+// into a negative duration.
+const maxBackoff = 5 * time.Minute
+
+// backoffWithJitter returns an exponential backoff duration for the given
+// retry attempt (1-indexed), with up to 50% random jitter added to avoid
+// thundering-herd retries.
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	shift := uint(attempt - 1)
+	if shift > 20 {
+		shift = 20
+	}
+	backoff := base * time.Duration(1<<shift)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+	return backoff + jitter
+}
+
+func getFilenameFromURL(ctx context.Context, httpClient *http.Client, url string) string {
 	filename := path.Base(url)
 
 	if filename == "." || filename == "/" {
@@ -377,13 +1130,155 @@ func getFilenameFromURL(url string) string {
 		}
 	}
 
-	if !isTarball(filename) {
+	if !isTarball(ctx, httpClient, url) {
 		filename = filename + ".tar.gz"
 	}
 
 	return filename
 }
 
+// gitFetcher materializes a git-source downloadLocation into a tarball so
+// the rest of the download/extraction pipeline never has to know the source
+// wasn't an ordinary archive. It clones into archivesDir/<repo>-<ref>/
+// (shallowly, except for a pinned commit, which needs full history),
+// checking out the pinned commit/tag/branch when one was given, and returns
+// the tarball path plus the resolved commit SHA.
+func gitFetcher(ctx context.Context, locator GitLocator, archivesDir string) (string, string, error) {
+	repoName := strings.TrimSuffix(path.Base(locator.RepoURL), ".git")
+	refLabel := locator.Ref
+	if refLabel == "" {
+		refLabel = "HEAD"
+	}
+	cloneDir := filepath.Join(archivesDir, fmt.Sprintf("%s-%s", repoName, sanitizeRefForPath(refLabel)))
+
+	cloneOpts := &git.CloneOptions{URL: locator.RepoURL}
+	switch locator.RefType {
+	case "branch":
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(locator.Ref)
+		cloneOpts.SingleBranch = true
+		cloneOpts.Depth = 1
+	case "tag":
+		cloneOpts.ReferenceName = plumbing.NewTagReferenceName(locator.Ref)
+		cloneOpts.SingleBranch = true
+		cloneOpts.Depth = 1
+	case "commit":
+		// A shallow clone can't see an arbitrary commit, so fetch full
+		// history and check it out explicitly below.
+	default:
+		cloneOpts.Depth = 1
+	}
+
+	repo, err := git.PlainCloneContext(ctx, cloneDir, false, cloneOpts)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to clone %s: %w", locator.RepoURL, err)
+	}
+
+	if locator.RefType == "commit" {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to open worktree for %s: %w", locator.RepoURL, err)
+		}
+		if err := worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(locator.Ref)}); err != nil {
+			return "", "", fmt.Errorf("failed to checkout commit %s for %s: %w", locator.Ref, locator.RepoURL, err)
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve HEAD for %s: %w", locator.RepoURL, err)
+	}
+	resolvedCommit := head.Hash().String()
+
+	tarballPath := cloneDir + ".tar.gz"
+	if err := tarGzDir(cloneDir, tarballPath); err != nil {
+		return "", "", fmt.Errorf("failed to materialize tarball for %s: %w", locator.RepoURL, err)
+	}
+
+	return tarballPath, resolvedCommit, nil
+}
+
+// sanitizeRefForPath makes a git ref safe to use as a directory name
+// component.
+func sanitizeRefForPath(ref string) string {
+	return strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(ref)
+}
+
+// tarGzDir writes a deterministic gzip-compressed tarball of srcDir (skipping
+// its .git metadata directory) to destTarball, so a git clone can flow
+// through the same extraction path as a downloaded archive.
+func tarGzDir(srcDir, destTarball string) error {
+	out, err := os.Create(destTarball)
+	if err != nil {
+		return fmt.Errorf("failed to create tarball: %w", err)
+	}
+	defer out.Close()
+
+	gzWriter := gzip.NewWriter(out)
+	defer gzWriter.Close()
+
+	tarWriter := tar.NewWriter(gzWriter)
+	defer tarWriter.Close()
+
+	return filepath.Walk(srcDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Name() == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, filePath)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", filePath, err)
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("failed to build tar header for %s: %w", filePath, err)
+		}
+		header.Name = relPath
+		// Drop checkout-time timestamps and local ownership so the same tree
+		// always produces the same tarball bytes.
+		header.ModTime = time.Unix(0, 0)
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header for %s: %w", filePath, err)
+		}
+
+		if info.Mode().IsRegular() {
+			file, err := os.Open(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", filePath, err)
+			}
+			defer file.Close()
+
+			if _, err := io.Copy(tarWriter, file); err != nil {
+				return fmt.Errorf("failed to write %s to tarball: %w", filePath, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 func extractArchives(archiveFiles []string, filePackages map[string][]string, extractDir string) ExtractionSummary {
 	successCount := 0
 	failureCount := 0
@@ -417,26 +1312,38 @@ func extractArchive(archiveFile, extractDir string) error {
 	}
 	defer file.Close()
 
-	var reader io.Reader
-	filename := strings.ToLower(archiveFile)
+	// Determine compression type from the file's actual contents rather than
+	// trusting its extension, since the URL it was saved under may lie.
+	codec, err := detectCompression(file)
+	if err != nil {
+		return fmt.Errorf("failed to detect archive codec: %w", err)
+	}
 
-	// Determine compression type and create appropriate reader
-	if strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz") {
+	var reader io.Reader
+	switch codec {
+	case CodecGzip:
 		gzReader, err := gzip.NewReader(file)
 		if err != nil {
 			return fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer gzReader.Close()
 		reader = gzReader
-	} else if strings.HasSuffix(filename, ".tar.xz") {
+	case CodecXz:
 		xzReader, err := xz.NewReader(file)
 		if err != nil {
 			return fmt.Errorf("failed to create xz reader: %w", err)
 		}
 		reader = xzReader
-	} else if strings.HasSuffix(filename, ".tar.bz2") {
+	case CodecBzip2:
 		reader = bzip2.NewReader(file)
-	} else {
+	case CodecZstd:
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+	default:
 		return fmt.Errorf("unsupported archive format: %s", archiveFile)
 	}
 
@@ -531,42 +1438,131 @@ func generateDefaultDownloadDir(imageRef string) string {
 	return imageName
 }
 
-func retrieveSBOMFromSigstore(imageRef, platform string) ([]byte, error) {
+// retrieveSBOMFromSigstore fetches an image's attestations, verifies each
+// against its Fulcio signing certificate and Rekor inclusion proof, and
+// returns the payload of the first verified SPDX attestation along with the
+// signer identity and Rekor log index that verified it.
+func retrieveSBOMFromSigstore(ctx context.Context, imageRef, platform string, attestOpts AttestationVerifyOptions) ([]byte, string, int64, error) {
 	ref, err := name.ParseReference(imageRef)
 	if err != nil {
-		return nil, fmt.Errorf("invalid image reference: %w", err)
+		return nil, "", 0, fmt.Errorf("invalid image reference: %w", err)
 	}
 
 	signedImg, err := remote.SignedImage(ref)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get signed image: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to get signed image: %w", err)
 	}
 
 	attestations, err := signedImg.Attestations()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get attestations: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to get attestations: %w", err)
 	}
 
 	attestationList, err := attestations.Get()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get attestation list: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to get attestation list: %w", err)
 	}
 
 	if len(attestationList) == 0 {
-		return nil, fmt.Errorf("no attestations found for image %s", imageRef)
+		return nil, "", 0, fmt.Errorf("no attestations found for image %s", imageRef)
+	}
+
+	checkOpts, err := buildAttestationCheckOpts(ctx, attestOpts)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to prepare attestation verification: %w", err)
+	}
+
+	verifiedAttestations, _, err := cosign.VerifyImageAttestations(ctx, ref, checkOpts)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to verify attestations for %s: %w", imageRef, err)
 	}
 
-	for _, attestation := range attestationList {
+	for _, attestation := range verifiedAttestations {
 		sbomData, err := extractSPDXFromAttestation(attestation)
-		if err != nil {
+		if err != nil || sbomData == nil {
 			continue
 		}
-		if sbomData != nil {
-			return sbomData, nil
+
+		identity, logIndex := signerIdentity(attestation)
+		return sbomData, identity, logIndex, nil
+	}
+
+	return nil, "", 0, fmt.Errorf("no verified SPDX attestations found for image %s", imageRef)
+}
+
+// buildAttestationCheckOpts assembles cosign's CheckOpts from the Fulcio and
+// Rekor roots (fetched via the sigstore TUF root of trust) and the
+// certificate-identity flags, mirroring `cosign verify-attestation`.
+func buildAttestationCheckOpts(ctx context.Context, attestOpts AttestationVerifyOptions) (*cosign.CheckOpts, error) {
+	checkOpts := &cosign.CheckOpts{
+		Identities: []cosign.Identity{
+			{
+				Subject:       attestOpts.CertificateIdentity,
+				SubjectRegExp: attestOpts.CertificateIdentityRegexp,
+				Issuer:        attestOpts.CertificateOidcIssuer,
+				IssuerRegExp:  attestOpts.CertificateOidcIssuerRegexp,
+			},
+		},
+		IgnoreTlog: attestOpts.InsecureIgnoreTlog,
+	}
+
+	rootCerts, err := fulcio.GetRoots()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Fulcio root certificates: %w", err)
+	}
+	checkOpts.RootCerts = rootCerts
+
+	intermediateCerts, err := fulcio.GetIntermediates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Fulcio intermediate certificates: %w", err)
+	}
+	checkOpts.IntermediateCerts = intermediateCerts
+
+	ctLogPubKeys, err := cosign.GetCTLogPubs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CT log public keys: %w", err)
+	}
+	checkOpts.CTLogPubKeys = ctLogPubKeys
+
+	if !attestOpts.InsecureIgnoreTlog {
+		rekorPubKeys, err := cosign.GetRekorPubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Rekor public keys: %w", err)
+		}
+		checkOpts.RekorPubKeys = rekorPubKeys
+
+		client, err := rekorclient.GetRekorClient(defaultRekorURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build Rekor client: %w", err)
+		}
+		checkOpts.RekorClient = client
+	}
+
+	return checkOpts, nil
+}
+
+// signerIdentity extracts a human-readable signer identity and the Rekor
+// transparency log index from a verified attestation, for display alongside
+// the SBOM it vouches for.
+func signerIdentity(attestation oci.Signature) (string, int64) {
+	var identity string
+	if cert, err := attestation.Cert(); err == nil && cert != nil {
+		switch {
+		case len(cert.EmailAddresses) > 0:
+			identity = cert.EmailAddresses[0]
+		case len(cert.URIs) > 0:
+			identity = cert.URIs[0].String()
+		default:
+			identity = cert.Subject.CommonName
 		}
 	}
 
-	return nil, fmt.Errorf("no SPDX attestations found for image %s", imageRef)
+	var logIndex int64
+	if rekorBundle, err := attestation.Bundle(); err == nil && rekorBundle != nil {
+		logIndex = rekorBundle.Payload.LogIndex
+	}
+
+	return identity, logIndex
 }
 
 func extractSPDXFromAttestation(attestation oci.Signature) ([]byte, error) {
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Chainguard, Inc.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitterNeverPanics(t *testing.T) {
+	bases := []time.Duration{time.Millisecond, 500 * time.Millisecond, 30 * time.Second}
+	for _, base := range bases {
+		for attempt := 1; attempt <= 100; attempt++ {
+			backoff := backoffWithJitter(base, attempt)
+			if backoff <= 0 {
+				t.Fatalf("backoffWithJitter(%s, %d) = %s, want a positive duration", base, attempt, backoff)
+			}
+			if backoff > maxBackoff+maxBackoff/2 {
+				t.Fatalf("backoffWithJitter(%s, %d) = %s, want at most ~1.5x maxBackoff", base, attempt, backoff)
+			}
+		}
+	}
+}
+
+func TestResumeMatchesPriorAttempt(t *testing.T) {
+	tests := []struct {
+		name  string
+		resp  *http.Response
+		state resumeState
+		want  bool
+	}{
+		{
+			name:  "no prior state always matches",
+			resp:  &http.Response{Header: http.Header{}},
+			state: resumeState{},
+			want:  true,
+		},
+		{
+			name:  "matching etag",
+			resp:  &http.Response{Header: http.Header{"Etag": []string{"abc"}}},
+			state: resumeState{etag: "abc"},
+			want:  true,
+		},
+		{
+			name:  "changed etag",
+			resp:  &http.Response{Header: http.Header{"Etag": []string{"def"}}},
+			state: resumeState{etag: "abc"},
+			want:  false,
+		},
+		{
+			name:  "matching last-modified",
+			resp:  &http.Response{Header: http.Header{"Last-Modified": []string{"Mon, 01 Jan 2024 00:00:00 GMT"}}},
+			state: resumeState{lastModified: "Mon, 01 Jan 2024 00:00:00 GMT"},
+			want:  true,
+		},
+		{
+			name:  "changed last-modified",
+			resp:  &http.Response{Header: http.Header{"Last-Modified": []string{"Tue, 02 Jan 2024 00:00:00 GMT"}}},
+			state: resumeState{lastModified: "Mon, 01 Jan 2024 00:00:00 GMT"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resumeMatchesPriorAttempt(tt.resp, tt.state); got != tt.want {
+				t.Errorf("resumeMatchesPriorAttempt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentRangeStartsAt(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentRange string
+		existingSize int64
+		want         bool
+	}{
+		{name: "matches existing size", contentRange: "bytes 1024-2047/4096", existingSize: 1024, want: true},
+		{name: "server restarted from zero", contentRange: "bytes 0-2047/4096", existingSize: 1024, want: false},
+		{name: "missing header", contentRange: "", existingSize: 1024, want: false},
+		{name: "malformed header", contentRange: "bytes weird", existingSize: 1024, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentRangeStartsAt(tt.contentRange, tt.existingSize); got != tt.want {
+				t.Errorf("contentRangeStartsAt(%q, %d) = %v, want %v", tt.contentRange, tt.existingSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGitLocation(t *testing.T) {
+	tests := []struct {
+		name             string
+		downloadLocation string
+		want             GitLocator
+	}{
+		{
+			name:             "commit pin",
+			downloadLocation: "git+https://github.com/foo/bar@abc123",
+			want:             GitLocator{RepoURL: "https://github.com/foo/bar", Ref: "abc123", RefType: "commit"},
+		},
+		{
+			name:             "tag pin",
+			downloadLocation: "git+https://github.com/foo/bar.git#tag=v1.2.3",
+			want:             GitLocator{RepoURL: "https://github.com/foo/bar.git", Ref: "v1.2.3", RefType: "tag"},
+		},
+		{
+			name:             "branch pin",
+			downloadLocation: "git+https://github.com/foo/bar.git#branch=main",
+			want:             GitLocator{RepoURL: "https://github.com/foo/bar.git", Ref: "main", RefType: "branch"},
+		},
+		{
+			name:             "ssh user@host is not mistaken for a commit pin",
+			downloadLocation: "git+ssh://git@github.com/foo/bar.git",
+			want:             GitLocator{RepoURL: "ssh://git@github.com/foo/bar.git"},
+		},
+		{
+			name:             "no ref",
+			downloadLocation: "git+https://github.com/foo/bar",
+			want:             GitLocator{RepoURL: "https://github.com/foo/bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGitLocation(tt.downloadLocation); got != tt.want {
+				t.Errorf("parseGitLocation(%q) = %+v, want %+v", tt.downloadLocation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustAlgorithms(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue string
+		want      map[string]bool
+	}{
+		{name: "empty trusts everything", flagValue: "", want: nil},
+		{name: "whitespace-only trusts everything", flagValue: "   ", want: nil},
+		{name: "single algorithm", flagValue: "sha256", want: map[string]bool{"sha256": true}},
+		{
+			name:      "multiple algorithms normalized",
+			flagValue: "SHA-256, sha512 ,blake3",
+			want:      map[string]bool{"sha256": true, "sha512": true, "blake3": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTrustAlgorithms(tt.flagValue)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTrustAlgorithms(%q) = %v, want %v", tt.flagValue, got, tt.want)
+			}
+			for algorithm := range tt.want {
+				if !got[algorithm] {
+					t.Errorf("parseTrustAlgorithms(%q) missing algorithm %q", tt.flagValue, algorithm)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	content := []byte("package contents")
+	sum := sha256.Sum256(content)
+	sha256Hex := hex.EncodeToString(sum[:])
+
+	path := filepath.Join(t.TempDir(), "package.tar.gz")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	t.Run("matching checksum is verified", func(t *testing.T) {
+		checksums := []Checksum{{Algorithm: "SHA-256", ChecksumValue: sha256Hex}}
+		verified, err := verifyChecksums(path, checksums, nil)
+		if err != nil {
+			t.Fatalf("verifyChecksums() error = %v", err)
+		}
+		if len(verified) != 1 || verified[0] != "sha256" {
+			t.Errorf("verifyChecksums() verified = %v, want [sha256]", verified)
+		}
+	})
+
+	t.Run("mismatched checksum is rejected", func(t *testing.T) {
+		checksums := []Checksum{{Algorithm: "SHA-256", ChecksumValue: "0000000000000000000000000000000000000000000000000000000000000000"}}
+		if _, err := verifyChecksums(path, checksums, nil); err == nil {
+			t.Fatal("verifyChecksums() expected a mismatch error, got nil")
+		}
+	})
+
+	t.Run("untrusted algorithm is skipped", func(t *testing.T) {
+		checksums := []Checksum{{Algorithm: "SHA-256", ChecksumValue: sha256Hex}}
+		verified, err := verifyChecksums(path, checksums, map[string]bool{"sha512": true})
+		if err != nil {
+			t.Fatalf("verifyChecksums() error = %v", err)
+		}
+		if len(verified) != 0 {
+			t.Errorf("verifyChecksums() verified = %v, want none", verified)
+		}
+	})
+}